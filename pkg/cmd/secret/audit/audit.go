@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"github.com/cli/cli/v2/pkg/cmd/secret/audit/show"
+	"github.com/cli/cli/v2/pkg/cmd/secret/audit/verify"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAudit(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit <command>",
+		Short: "Inspect the local secret audit log",
+		Long:  "Work with the tamper-evident log that `gh secret set --audit` appends to on every successful secret upload.",
+	}
+
+	cmd.AddCommand(show.NewCmdShow(f, nil))
+	cmd.AddCommand(verify.NewCmdVerify(f, nil))
+
+	return cmd
+}
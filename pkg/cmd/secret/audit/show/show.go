@@ -0,0 +1,71 @@
+package show
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmd/secret/shared"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/cli/cli/v2/utils"
+	"github.com/spf13/cobra"
+)
+
+type ShowOptions struct {
+	IO *iostreams.IOStreams
+
+	LogPath string
+}
+
+func NewCmdShow(f *cmdutil.Factory, runF func(*ShowOptions) error) *cobra.Command {
+	opts := &ShowOptions{
+		IO: f.IOStreams,
+	}
+
+	var logPath string
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the local secret audit log",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if logPath == "" {
+				var err error
+				logPath, err = shared.DefaultAuditLogPath()
+				if err != nil {
+					return err
+				}
+			}
+			opts.LogPath = logPath
+
+			if runF != nil {
+				return runF(opts)
+			}
+
+			return showRun(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&logPath, "log-file", "", "Path to the secret audit log (default \"~/.config/gh/secret-audit.log\")")
+
+	return cmd
+}
+
+func showRun(opts *ShowOptions) error {
+	entries, err := shared.ReadAuditLog(opts.LogPath)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	tp := utils.NewTablePrinter(opts.IO)
+
+	for _, entry := range entries {
+		tp.AddField(entry.Timestamp.Format("2006-01-02T15:04:05Z07:00"))
+		tp.AddField(string(entry.Scope))
+		tp.AddField(entry.SecretName)
+		tp.AddField(entry.Actor)
+		tp.AddField(entry.CiphertextSHA256)
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}
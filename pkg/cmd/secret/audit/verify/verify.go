@@ -0,0 +1,66 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmd/secret/shared"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type VerifyOptions struct {
+	IO *iostreams.IOStreams
+
+	LogPath string
+}
+
+func NewCmdVerify(f *cmdutil.Factory, runF func(*VerifyOptions) error) *cobra.Command {
+	opts := &VerifyOptions{
+		IO: f.IOStreams,
+	}
+
+	var logPath string
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the integrity of the local secret audit log",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if logPath == "" {
+				var err error
+				logPath, err = shared.DefaultAuditLogPath()
+				if err != nil {
+					return err
+				}
+			}
+			opts.LogPath = logPath
+
+			if runF != nil {
+				return runF(opts)
+			}
+
+			return verifyRun(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&logPath, "log-file", "", "Path to the secret audit log (default \"~/.config/gh/secret-audit.log\")")
+
+	return cmd
+}
+
+func verifyRun(opts *VerifyOptions) error {
+	brokenAt, err := shared.VerifyAuditLog(opts.LogPath)
+	if err != nil {
+		return fmt.Errorf("failed to verify audit log: %w", err)
+	}
+
+	cs := opts.IO.ColorScheme()
+
+	if brokenAt == -1 {
+		fmt.Fprintf(opts.IO.Out, "%s Audit log is intact\n", cs.SuccessIconWithColor(cs.Green))
+		return nil
+	}
+
+	return fmt.Errorf("audit log is broken starting at entry %d: hash chain does not match", brokenAt)
+}
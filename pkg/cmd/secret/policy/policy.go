@@ -0,0 +1,235 @@
+// Package policy implements a local allow/deny policy engine that
+// pkg/cmd/secret/set consults before uploading a secret to GitHub.
+package policy
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scope is the level at which a secret is being set.
+type Scope string
+
+const (
+	ScopeRepository   Scope = "repo"
+	ScopeOrganization Scope = "org"
+	ScopeEnvironment  Scope = "env"
+	ScopeUser         Scope = "user"
+)
+
+type ScopeRules struct {
+	NameAllow []string `yaml:"name_allow,omitempty"`
+	NameDeny  []string `yaml:"name_deny,omitempty"`
+}
+
+type ValuePattern struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+type EntropyRule struct {
+	NamePattern string  `yaml:"name_pattern"`
+	BitsPerChar float64 `yaml:"bits_per_char"`
+}
+
+type VisibilityRule struct {
+	NamePattern  string `yaml:"name_pattern"`
+	Scope        Scope  `yaml:"scope"`
+	Visibility   string `yaml:"visibility"`
+	RequireRepos bool   `yaml:"require_repos"`
+}
+
+// Policy is the parsed contents of a secret-policy.yml file.
+type Policy struct {
+	Scopes             map[Scope]ScopeRules `yaml:"scopes"`
+	ForbiddenValues    []ValuePattern       `yaml:"forbidden_value_patterns"`
+	MinEntropy         []EntropyRule        `yaml:"min_entropy"`
+	RequiredVisibility []VisibilityRule     `yaml:"required_visibility"`
+}
+
+// Secret is the subset of a pending secret-set operation that policy rules
+// are evaluated against.
+type Secret struct {
+	Name            string
+	Value           []byte
+	Scope           Scope
+	Visibility      string
+	RepositoryNames []string
+}
+
+// Violation describes a single rule that a Secret failed.
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// DefaultPath returns the default location of the policy file,
+// ~/.config/gh/secret-policy.yml.
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "gh", "secret-policy.yml"), nil
+}
+
+// Load reads and parses a policy file from disk.
+func Load(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parse(f)
+}
+
+func parse(r io.Reader) (*Policy, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("could not parse policy file: %w", err)
+	}
+	return &p, nil
+}
+
+// Check evaluates all applicable rules against s and returns every
+// violation found. A nil Policy has no rules and always returns no
+// violations.
+func (p *Policy) Check(s Secret) ([]Violation, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var violations []Violation
+
+	if rules, ok := p.Scopes[s.Scope]; ok {
+		for _, pattern := range rules.NameDeny {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid name_deny pattern %q: %w", pattern, err)
+			}
+			if re.MatchString(s.Name) {
+				violations = append(violations, Violation{
+					Rule:    "name_deny",
+					Message: fmt.Sprintf("secret name %q matches denied pattern %q", s.Name, pattern),
+				})
+			}
+		}
+		if len(rules.NameAllow) > 0 {
+			allowed := false
+			for _, pattern := range rules.NameAllow {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("invalid name_allow pattern %q: %w", pattern, err)
+				}
+				if re.MatchString(s.Name) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				violations = append(violations, Violation{
+					Rule:    "name_allow",
+					Message: fmt.Sprintf("secret name %q does not match any allowed pattern for scope %q", s.Name, s.Scope),
+				})
+			}
+		}
+	}
+
+	for _, fv := range p.ForbiddenValues {
+		re, err := regexp.Compile(fv.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forbidden_value_patterns pattern %q: %w", fv.Pattern, err)
+		}
+		if re.Match(s.Value) {
+			violations = append(violations, Violation{
+				Rule:    "forbidden_value",
+				Message: fmt.Sprintf("secret value matches forbidden pattern %q", fv.Name),
+			})
+		}
+	}
+
+	for _, er := range p.MinEntropy {
+		re, err := regexp.Compile(er.NamePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_entropy name_pattern %q: %w", er.NamePattern, err)
+		}
+		if !re.MatchString(s.Name) {
+			continue
+		}
+		if bits := shannonEntropyPerChar(s.Value); bits < er.BitsPerChar {
+			violations = append(violations, Violation{
+				Rule:    "min_entropy",
+				Message: fmt.Sprintf("secret %q looks like a low-entropy value (%.2f bits/char, want >= %.2f)", s.Name, bits, er.BitsPerChar),
+			})
+		}
+	}
+
+	for _, vr := range p.RequiredVisibility {
+		if vr.Scope != "" && vr.Scope != s.Scope {
+			continue
+		}
+		re, err := regexp.Compile(vr.NamePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid required_visibility name_pattern %q: %w", vr.NamePattern, err)
+		}
+		if !re.MatchString(s.Name) {
+			continue
+		}
+		if s.Visibility != vr.Visibility {
+			violations = append(violations, Violation{
+				Rule:    "required_visibility",
+				Message: fmt.Sprintf("secret %q must use visibility %q, got %q", s.Name, vr.Visibility, s.Visibility),
+			})
+			continue
+		}
+		if vr.RequireRepos && len(s.RepositoryNames) == 0 {
+			violations = append(violations, Violation{
+				Rule:    "required_visibility",
+				Message: fmt.Sprintf("secret %q must specify an explicit repository list", s.Name),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// shannonEntropyPerChar estimates the Shannon entropy of b in bits per
+// character, used as a rough signal for "this doesn't look like a random
+// token".
+func shannonEntropyPerChar(b []byte) float64 {
+	if len(b) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, c := range b {
+		counts[c]++
+	}
+
+	var entropy float64
+	n := float64(len(b))
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
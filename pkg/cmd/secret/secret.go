@@ -0,0 +1,25 @@
+package secret
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	cmdAudit "github.com/cli/cli/v2/pkg/cmd/secret/audit"
+	cmdSet "github.com/cli/cli/v2/pkg/cmd/secret/set"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdSecret(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secret <command>",
+		Short: "Manage GitHub secrets",
+		Long: heredoc.Doc(`
+			Secrets can be set at the repository, environment, organization, or user
+			level for use in GitHub Actions, Dependabot, or Codespaces.
+		`),
+	}
+
+	cmd.AddCommand(cmdSet.NewCmdSet(f, nil))
+	cmd.AddCommand(cmdAudit.NewCmdAudit(f))
+
+	return cmd
+}
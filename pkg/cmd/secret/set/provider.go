@@ -0,0 +1,78 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SecretProvider resolves a secret reference URI (e.g. "vault://secret/data/prod#api_key")
+// to the secret value it points at.
+type SecretProvider interface {
+	// Scheme is the URI scheme this provider handles, e.g. "vault".
+	Scheme() string
+	Resolve(ctx context.Context, ref string) ([]byte, error)
+}
+
+var providerRegistry = map[string]SecretProvider{}
+
+// RegisterProvider makes a SecretProvider available under its scheme, so
+// that third parties can add support for additional secret stores.
+func RegisterProvider(p SecretProvider) {
+	providerRegistry[p.Scheme()] = p
+}
+
+func init() {
+	RegisterProvider(&vaultProvider{})
+	RegisterProvider(&awsSecretsManagerProvider{})
+	RegisterProvider(&onePasswordProvider{})
+}
+
+// refScheme returns the scheme portion of a secret reference ("vault" for
+// "vault://..."), or "" if ref does not look like a URI.
+func refScheme(ref string) string {
+	idx := strings.Index(ref, "://")
+	if idx < 0 {
+		return ""
+	}
+	return ref[:idx]
+}
+
+// resolveRef looks up a provider for ref's scheme and resolves it. The
+// second return value reports whether ref was recognized as a provider
+// reference at all; callers should fall back to treating ref literally
+// when it is false.
+func resolveRef(ctx context.Context, ref string) ([]byte, bool, error) {
+	scheme := refScheme(ref)
+	if scheme == "" {
+		return nil, false, nil
+	}
+
+	provider, ok := providerRegistry[scheme]
+	if !ok {
+		return nil, false, nil
+	}
+
+	value, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to resolve %q: %w", ref, err)
+	}
+	return value, true, nil
+}
+
+// resolveSecretValue resolves value through the provider registry unless
+// opts.Resolve is false, in which case value is used literally.
+func resolveSecretValue(opts *SetOptions, value []byte) ([]byte, error) {
+	if !opts.Resolve {
+		return value, nil
+	}
+
+	resolved, matched, err := resolveRef(context.Background(), string(value))
+	if err != nil {
+		return nil, err
+	}
+	if !matched {
+		return value, nil
+	}
+	return resolved, nil
+}
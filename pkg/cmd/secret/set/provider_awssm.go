@@ -0,0 +1,50 @@
+package set
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// awsSecretsManagerProvider resolves "awssm://secret-id#field" references by
+// shelling out to the AWS CLI (`aws secretsmanager get-secret-value`), using
+// whatever credentials the AWS CLI itself is configured with.
+type awsSecretsManagerProvider struct{}
+
+func (p *awsSecretsManagerProvider) Scheme() string { return "awssm" }
+
+func (p *awsSecretsManagerProvider) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	secretID, field, err := parseRef(ref, "awssm")
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "aws", "secretsmanager", "get-secret-value",
+		"--secret-id", secretID, "--query", "SecretString", "--output", "text")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("aws secretsmanager get-secret-value %s: %w: %s", secretID, err, strings.TrimSpace(stderr.String()))
+	}
+
+	secretString := strings.TrimRight(stdout.String(), "\n")
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(secretString), &fields); err != nil {
+		// Not a JSON object; treat the whole secret as the value, ignoring
+		// the requested field name.
+		return []byte(secretString), nil
+	}
+
+	value, ok := fields[field]
+	if !ok {
+		return nil, fmt.Errorf("field %q not found in secret %q", field, secretID)
+	}
+
+	return []byte(value), nil
+}
@@ -0,0 +1,28 @@
+package set
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// onePasswordProvider resolves "op://vault/item/field" references by
+// shelling out to the 1Password CLI (`op read`).
+type onePasswordProvider struct{}
+
+func (p *onePasswordProvider) Scheme() string { return "op" }
+
+func (p *onePasswordProvider) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "op", "read", ref)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("op read %s: %w: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return bytes.TrimRight(stdout.Bytes(), "\n"), nil
+}
@@ -0,0 +1,66 @@
+package set
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_vaultProvider_Resolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/prod", r.URL.Path)
+		assert.Equal(t, "s.faketoken", r.Header.Get("X-Vault-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"data":{"api_key":"sekrit"}}}`))
+	}))
+	defer srv.Close()
+
+	p := &vaultProvider{
+		HTTPClient: srv.Client(),
+		Addr:       srv.URL,
+		Token:      "s.faketoken",
+	}
+
+	value, err := p.Resolve(context.Background(), "vault://secret/data/prod#api_key")
+	assert.NoError(t, err)
+	assert.Equal(t, "sekrit", string(value))
+}
+
+func Test_vaultProvider_Resolve_missingField(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"data":{"other_key":"sekrit"}}}`))
+	}))
+	defer srv.Close()
+
+	p := &vaultProvider{HTTPClient: srv.Client(), Addr: srv.URL, Token: "s.faketoken"}
+
+	_, err := p.Resolve(context.Background(), "vault://secret/data/prod#api_key")
+	assert.Error(t, err)
+}
+
+func Test_resolveRef_unrecognizedScheme(t *testing.T) {
+	value, matched, err := resolveRef(context.Background(), "not-a-uri-at-all")
+	assert.NoError(t, err)
+	assert.False(t, matched)
+	assert.Nil(t, value)
+}
+
+func Test_resolveSecretValue_literalWhenResolveDisabled(t *testing.T) {
+	opts := &SetOptions{Resolve: false}
+	value, err := resolveSecretValue(opts, []byte("vault://secret/data/prod#api_key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "vault://secret/data/prod#api_key", string(value))
+}
+
+func Test_parseRef(t *testing.T) {
+	path, field, err := parseRef("vault://secret/data/prod#api_key", "vault")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret/data/prod", path)
+	assert.Equal(t, "api_key", field)
+
+	_, _, err = parseRef("vault://secret/data/prod", "vault")
+	assert.Error(t, err)
+}
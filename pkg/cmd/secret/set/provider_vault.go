@@ -0,0 +1,102 @@
+package set
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// vaultProvider resolves "vault://path/to/secret#field" references against
+// a HashiCorp Vault KV v2 mount, using VAULT_ADDR and VAULT_TOKEN.
+type vaultProvider struct {
+	// HTTPClient, when set, is used instead of http.DefaultClient. Tests
+	// point it at a fake Vault server.
+	HTTPClient *http.Client
+	// Addr and Token override VAULT_ADDR/VAULT_TOKEN, for tests.
+	Addr  string
+	Token string
+}
+
+func (p *vaultProvider) Scheme() string { return "vault" }
+
+type vaultKVv2Response struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+func (p *vaultProvider) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	path, field, err := parseRef(ref, "vault")
+	if err != nil {
+		return nil, err
+	}
+
+	addr := p.Addr
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if addr == "" {
+		return nil, fmt.Errorf("VAULT_ADDR is not set")
+	}
+
+	token := p.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN is not set")
+	}
+
+	url := strings.TrimRight(addr, "/") + "/v1/" + path
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var body vaultKVv2Response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("could not parse vault response: %w", err)
+	}
+
+	value, ok := body.Data.Data[field]
+	if !ok {
+		return nil, fmt.Errorf("field %q not found at vault path %q", field, path)
+	}
+
+	return []byte(value), nil
+}
+
+// parseRef splits a "<scheme>://path#field" reference into its path and
+// field parts, requiring both to be present.
+func parseRef(ref, scheme string) (path, field string, err error) {
+	rest := strings.TrimPrefix(ref, scheme+"://")
+	idx := strings.LastIndexByte(rest, '#')
+	if idx < 0 {
+		return "", "", fmt.Errorf("%s reference %q is missing a #field suffix", scheme, ref)
+	}
+	path, field = rest[:idx], rest[idx+1:]
+	if path == "" || field == "" {
+		return "", "", fmt.Errorf("%s reference %q is missing a path or field", scheme, ref)
+	}
+	return path, field, nil
+}
@@ -0,0 +1,711 @@
+package set
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/secret/policy"
+	"github.com/cli/cli/v2/pkg/cmd/secret/shared"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/cli/cli/v2/pkg/prompt"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/nacl/box"
+)
+
+type SetOptions struct {
+	HttpClient func() (*http.Client, error)
+	IO         *iostreams.IOStreams
+	Config     func() (config.Config, error)
+	BaseRepo   func() (ghrepo.Interface, error)
+
+	RandomOverride io.Reader
+
+	SecretName      string
+	OrgName         string
+	EnvName         string
+	UserSecrets     bool
+	Body            string
+	EnvFile         string
+	DoNotStore      bool
+	Visibility      shared.Visibility
+	RepositoryNames []string
+
+	Policy     *policy.Policy
+	PolicyWarn bool
+
+	Resolve bool
+
+	Audit     bool
+	AuditSink shared.AuditSink
+
+	DryRun     bool
+	DryRunDiff bool
+}
+
+// namedSecret is a single KEY/value pair destined for the API, used both for
+// the single-secret case and for the batch of secrets read from --env-file.
+type namedSecret struct {
+	Name string
+	Body []byte
+}
+
+func NewCmdSet(f *cmdutil.Factory, runF func(*SetOptions) error) *cobra.Command {
+	opts := &SetOptions{
+		IO:         f.IOStreams,
+		Config:     f.Config,
+		HttpClient: f.HttpClient,
+	}
+
+	var orgName string
+	var envName string
+	var userSecrets bool
+	var visibility string
+	var repositoryNames []string
+	var doNotStore bool
+	var body string
+	var fromFile string
+	var envFile string
+	var usePolicy bool
+	var policyFile string
+	var policyWarn bool
+	var resolve bool = true
+	var audit bool
+	var dryRun string
+
+	cmd := &cobra.Command{
+		Use:   "set <secret-name>",
+		Short: "Create or update secrets",
+		Long: heredoc.Doc(`
+			Set a value for a secret on one of the following levels:
+			- repository (default): available to Actions runs or Dependabot in a repository
+			- environment: available to Actions runs for a deployment environment in a repository
+			- organization: available to Actions runs or Dependabot within an organization
+			- user: available to Codespaces for your user
+
+			Organization and user secrets can optionally be restricted to only be available to
+			specific repositories.
+
+			Secret values are locally encrypted before being sent to GitHub.
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if envFile != "" {
+				if len(args) > 0 {
+					return cmdutil.FlagErrorf("cannot specify a secret name when using `--env-file`")
+				}
+				if body != "" || fromFile != "" {
+					return cmdutil.FlagErrorf("specify only one of `--env-file`, `--body`, or `--from-file`")
+				}
+			} else if len(args) != 1 {
+				return cmdutil.FlagErrorf("must pass a single secret name")
+			} else {
+				opts.SecretName = args[0]
+			}
+
+			if body != "" && fromFile != "" {
+				return cmdutil.FlagErrorf("specify only one of `--body` or `--from-file`")
+			}
+
+			opts.Body = body
+			if fromFile != "" {
+				opts.Body = "@" + fromFile
+			}
+			opts.EnvFile = envFile
+
+			if err := cmdutil.MutuallyExclusive(
+				"specify only one of `--org`, `--env`, or `--user`",
+				orgName != "",
+				envName != "",
+				userSecrets,
+			); err != nil {
+				return err
+			}
+
+			opts.OrgName = orgName
+			opts.EnvName = envName
+			opts.UserSecrets = userSecrets
+
+			if !cmd.Flags().Changed("visibility") && len(repositoryNames) > 0 {
+				visibility = string(shared.Selected)
+			}
+
+			opts.Visibility = shared.Visibility(visibility)
+			if opts.Visibility != shared.All && opts.Visibility != shared.Private && opts.Visibility != shared.Selected {
+				return cmdutil.FlagErrorf("unknown visibility: %s", visibility)
+			}
+			if opts.Visibility == shared.Selected && len(repositoryNames) == 0 {
+				return cmdutil.FlagErrorf("`--repos` flag is required with `--visibility=selected`")
+			}
+			if cmd.Flags().Changed("visibility") && opts.Visibility != shared.Selected && len(repositoryNames) > 0 {
+				return cmdutil.FlagErrorf("`--repos` is only supported with `--visibility=selected`")
+			}
+			if opts.Visibility != shared.Private && orgName == "" && !userSecrets {
+				return cmdutil.FlagErrorf("`--visibility` is only supported with `--org` or `--user`")
+			}
+
+			opts.RepositoryNames = repositoryNames
+			opts.DoNotStore = doNotStore
+
+			if err := cmdutil.MutuallyExclusive(
+				"specify only one of `--no-store` or `--dry-run`",
+				doNotStore,
+				dryRun != "",
+			); err != nil {
+				return err
+			}
+
+			if usePolicy || policyFile != "" {
+				path := policyFile
+				if path == "" {
+					var err error
+					path, err = policy.DefaultPath()
+					if err != nil {
+						return err
+					}
+				}
+				p, err := policy.Load(path)
+				if err != nil {
+					return fmt.Errorf("failed to load policy file %s: %w", path, err)
+				}
+				opts.Policy = p
+			}
+			opts.PolicyWarn = policyWarn
+			opts.Resolve = resolve
+			opts.Audit = audit
+
+			if dryRun != "" {
+				if dryRun != "true" && dryRun != "diff" {
+					return cmdutil.FlagErrorf("`--dry-run` accepts no value or `diff`")
+				}
+				opts.DryRun = true
+				opts.DryRunDiff = dryRun == "diff"
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+
+			return setRun(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&body, "body", "b", "", "The value for the secret (reads from standard input if not specified). Supports `@path/to/file` to read the value from a local file.")
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "Load the secret value from a local `file`")
+	cmd.Flags().StringVarP(&envFile, "env-file", "f", "", "Load multiple secrets from a dotenv-formatted `file`")
+	cmd.Flags().StringVarP(&visibility, "visibility", "v", "private", "Set visibility for an organization secret: `{all|private|selected}`")
+	cmd.Flags().StringSliceVarP(&repositoryNames, "repos", "r", []string{}, "List of `repositories` that can access an organization or user secret")
+	cmd.Flags().StringVarP(&orgName, "org", "o", "", "Set `organization` secret")
+	cmd.Flags().StringVarP(&envName, "env", "e", "", "Set deployment `environment` secret")
+	cmd.Flags().BoolVarP(&userSecrets, "user", "u", false, "Set a secret for your user")
+	cmd.Flags().BoolVar(&doNotStore, "no-store", false, "Print the encrypted, base64-encoded value instead of storing it on Github")
+	cmd.Flags().BoolVar(&usePolicy, "policy", false, "Enforce the local secret naming/value policy before uploading")
+	cmd.Flags().StringVar(&policyFile, "policy-file", "", "Path to a policy `file` (default \"~/.config/gh/secret-policy.yml\")")
+	cmd.Flags().BoolVar(&policyWarn, "policy-warn", false, "Downgrade policy violations to warnings instead of aborting")
+	cmd.Flags().BoolVar(&resolve, "resolve", true, "Resolve provider references such as vault://, awssm://, and op:// (pass --resolve=false to use the value literally)")
+	cmd.Flags().BoolVar(&audit, "audit", false, "Append a tamper-evident record of this operation to the local secret audit log")
+	cmd.Flags().StringVar(&dryRun, "dry-run", "", "Print the planned operation as JSON instead of uploading it; pass `diff` to also report whether it would create or update the secret")
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = "true"
+
+	return cmd
+}
+
+func setRun(opts *SetOptions) error {
+	secrets, err := getSecretsToSet(opts)
+	if err != nil {
+		return fmt.Errorf("did not understand secret body: %w", err)
+	}
+
+	c, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	if !opts.Audit {
+		if v, _ := c.Get("", "audit"); v == "true" {
+			opts.Audit = true
+		}
+	}
+	if opts.Audit && opts.AuditSink == nil {
+		path, err := shared.DefaultAuditLogPath()
+		if err != nil {
+			return err
+		}
+		opts.AuditSink = &shared.FileAuditSink{Path: path}
+	}
+
+	httpClient, err := opts.HttpClient()
+	if err != nil {
+		return err
+	}
+	client := api.NewClientFromHTTP(httpClient)
+
+	orgName := opts.OrgName
+	envName := opts.EnvName
+
+	var host string
+	var baseRepo ghrepo.Interface
+	if orgName == "" && !opts.UserSecrets {
+		baseRepo, err = opts.BaseRepo()
+		if err != nil {
+			return err
+		}
+		host = baseRepo.RepoHost()
+	} else {
+		host, err = c.DefaultHost()
+		if err != nil {
+			return err
+		}
+	}
+
+	var pubKey *PubKey
+	switch {
+	case orgName != "":
+		pubKey, err = getPubKey(client, host, fmt.Sprintf("orgs/%s/actions/secrets/public-key", orgName))
+	case opts.UserSecrets:
+		pubKey, err = getPubKey(client, host, "user/codespaces/secrets/public-key")
+	case envName != "":
+		pubKey, err = getPubKey(client, host, fmt.Sprintf("repos/%s/environments/%s/secrets/public-key",
+			ghrepo.FullName(baseRepo), envName))
+	default:
+		pubKey, err = getPubKey(client, host, fmt.Sprintf("repos/%s/actions/secrets/public-key", ghrepo.FullName(baseRepo)))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch public key: %w", err)
+	}
+
+	var repositoryIDs []int64
+	if opts.Visibility == shared.Selected && len(opts.RepositoryNames) > 0 {
+		repositoryIDs, err = mapRepoNamesToIDs(client, host, orgName, opts.RepositoryNames)
+		if err != nil {
+			return fmt.Errorf("failed to look up IDs for repositories %v: %w", opts.RepositoryNames, err)
+		}
+	}
+
+	scope := policyScope(orgName, envName, opts.UserSecrets)
+
+	var dryRunManifests []dryRunManifest
+
+	for _, secret := range secrets {
+		violations, err := opts.Policy.Check(policy.Secret{
+			Name:            secret.Name,
+			Value:           secret.Body,
+			Scope:           scope,
+			Visibility:      string(opts.Visibility),
+			RepositoryNames: opts.RepositoryNames,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to evaluate secret policy: %w", err)
+		}
+		if len(violations) > 0 {
+			if !opts.PolicyWarn {
+				lines := make([]string, len(violations))
+				for i, v := range violations {
+					lines[i] = v.String()
+				}
+				return fmt.Errorf("secret %q violates policy:\n%s", secret.Name, strings.Join(lines, "\n"))
+			}
+			for _, v := range violations {
+				fmt.Fprintf(opts.IO.ErrOut, "! Policy warning for %q: %s\n", secret.Name, v)
+			}
+		}
+
+		encrypted, err := encryptSecret(opts, pubKey.Raw(), secret.Body)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt body: %w", err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(encrypted)
+
+		if opts.DoNotStore {
+			fmt.Fprintf(opts.IO.Out, "%s\n", encoded)
+			continue
+		}
+
+		var payload interface{}
+		var path string
+
+		switch {
+		case orgName != "":
+			repoIDs := make([]int, 0, len(repositoryIDs))
+			for _, id := range repositoryIDs {
+				repoIDs = append(repoIDs, int(id))
+			}
+			sort.Ints(repoIDs)
+			payload = SecretPayload{
+				EncryptedValue: encoded,
+				Visibility:     opts.Visibility,
+				Repositories:   repoIDs,
+				KeyID:          pubKey.ID,
+			}
+			path = fmt.Sprintf("orgs/%s/actions/secrets/%s", orgName, secret.Name)
+		case opts.UserSecrets:
+			repoIDs := make([]string, 0, len(repositoryIDs))
+			for _, id := range repositoryIDs {
+				repoIDs = append(repoIDs, strconv.FormatInt(id, 10))
+			}
+			sort.Strings(repoIDs)
+			payload = CodespacesSecretPayload{
+				EncryptedValue: encoded,
+				KeyID:          pubKey.ID,
+				Repositories:   repoIDs,
+			}
+			path = fmt.Sprintf("user/codespaces/secrets/%s", secret.Name)
+		case envName != "":
+			payload = SecretPayload{
+				EncryptedValue: encoded,
+				KeyID:          pubKey.ID,
+			}
+			path = fmt.Sprintf("repos/%s/environments/%s/secrets/%s", ghrepo.FullName(baseRepo), envName, secret.Name)
+		default:
+			payload = SecretPayload{
+				EncryptedValue: encoded,
+				KeyID:          pubKey.ID,
+			}
+			path = fmt.Sprintf("repos/%s/actions/secrets/%s", ghrepo.FullName(baseRepo), secret.Name)
+		}
+
+		if opts.DryRun {
+			manifest, err := buildDryRunManifest(client, host, path, scope, opts.Visibility, repositoryIDs, pubKey, secret, encrypted, opts.DryRunDiff)
+			if err != nil {
+				return err
+			}
+			dryRunManifests = append(dryRunManifests, manifest)
+			continue
+		}
+
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		if err := client.REST(host, "PUT", path, bytes.NewReader(payloadBytes), nil); err != nil {
+			return fmt.Errorf("failed to set secret %q: %w", secret.Name, err)
+		}
+
+		if opts.Audit && opts.AuditSink != nil {
+			actor, _ := c.Get(host, "user")
+			entity, err := shared.GetSecretEntity(opts.UserSecrets, orgName, envName)
+			if err != nil {
+				return err
+			}
+			ciphertextSum := sha256.Sum256(encrypted)
+			err = opts.AuditSink.Append(shared.AuditEntry{
+				Timestamp:        time.Now(),
+				Actor:            actor,
+				Scope:            entity,
+				SecretName:       secret.Name,
+				CiphertextSHA256: hex.EncodeToString(ciphertextSum[:]),
+				KeyID:            pubKey.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to append to secret audit log: %w", err)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		out, err := json.MarshalIndent(dryRunManifests, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(opts.IO.Out, string(out))
+		return nil
+	}
+
+	if opts.DoNotStore {
+		return nil
+	}
+
+	if opts.IO.IsStdoutTTY() {
+		target := orgName
+		if target == "" {
+			target = "your repository"
+			if opts.UserSecrets {
+				target = "your user"
+			} else if envName != "" {
+				target = fmt.Sprintf("environment %s", envName)
+			}
+		}
+		if len(secrets) == 1 {
+			fmt.Fprintf(opts.IO.Out, "%s Set secret %s for %s\n", cs(opts).SuccessIconWithColor(cs(opts).Green), secrets[0].Name, target)
+		} else {
+			fmt.Fprintf(opts.IO.Out, "%s Set %d secrets for %s\n", cs(opts).SuccessIconWithColor(cs(opts).Green), len(secrets), target)
+		}
+	}
+
+	return nil
+}
+
+// dryRunManifest describes a single secret-set operation that --dry-run
+// would have performed, without ever calling the PUT endpoint.
+type dryRunManifest struct {
+	Scope             string            `json:"scope"`
+	SecretName        string            `json:"secret_name"`
+	Visibility        shared.Visibility `json:"visibility,omitempty"`
+	RepositoryIDs     []int64           `json:"repository_ids,omitempty"`
+	KeyID             string            `json:"key_id"`
+	CiphertextBytes   int               `json:"ciphertext_bytes"`
+	ContentHash       string            `json:"content_hash"`
+	Operation         string            `json:"operation,omitempty"`
+	ExistingUpdatedAt *time.Time        `json:"existing_updated_at,omitempty"`
+}
+
+func buildDryRunManifest(client *api.Client, host, path string, scope policy.Scope, visibility shared.Visibility, repositoryIDs []int64, pubKey *PubKey, secret namedSecret, encrypted []byte, diff bool) (dryRunManifest, error) {
+	// Hash the plaintext body rather than encrypted, which is sealed with a
+	// fresh ephemeral key/nonce on every call and would never be stable
+	// across repeated --dry-run invocations of the same secret.
+	hash := sha256.Sum256(append([]byte(string(scope)+"|"+secret.Name+"|"+string(visibility)+"|"), secret.Body...))
+
+	manifest := dryRunManifest{
+		Scope:           string(scope),
+		SecretName:      secret.Name,
+		Visibility:      visibility,
+		RepositoryIDs:   repositoryIDs,
+		KeyID:           pubKey.ID,
+		CiphertextBytes: len(encrypted),
+		ContentHash:     hex.EncodeToString(hash[:]),
+	}
+
+	if !diff {
+		return manifest, nil
+	}
+
+	var existing struct {
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+	err := client.REST(host, "GET", path, nil, &existing)
+	var httpErr api.HTTPError
+	switch {
+	case errors.As(err, &httpErr) && httpErr.StatusCode == 404:
+		manifest.Operation = "create"
+	case err != nil:
+		return manifest, fmt.Errorf("failed to check existing secret %q: %w", secret.Name, err)
+	default:
+		manifest.Operation = "update"
+		manifest.ExistingUpdatedAt = existing.UpdatedAt
+	}
+
+	return manifest, nil
+}
+
+func policyScope(orgName, envName string, userSecrets bool) policy.Scope {
+	switch {
+	case orgName != "":
+		return policy.ScopeOrganization
+	case userSecrets:
+		return policy.ScopeUser
+	case envName != "":
+		return policy.ScopeEnvironment
+	default:
+		return policy.ScopeRepository
+	}
+}
+
+func cs(opts *SetOptions) *iostreams.ColorScheme {
+	return opts.IO.ColorScheme()
+}
+
+// getSecretsToSet resolves the single- and multi-secret input modes into a
+// common list of name/value pairs for setRun to upload.
+func getSecretsToSet(opts *SetOptions) ([]namedSecret, error) {
+	var secrets []namedSecret
+
+	if opts.EnvFile != "" {
+		f, err := os.Open(opts.EnvFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not open env file: %w", err)
+		}
+		defer f.Close()
+		secrets, err = parseDotenv(f)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		body, err := getBody(opts)
+		if err != nil {
+			return nil, err
+		}
+		secrets = []namedSecret{{Name: opts.SecretName, Body: body}}
+	}
+
+	for i, secret := range secrets {
+		resolved, err := resolveSecretValue(opts, secret.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve secret %q: %w", secret.Name, err)
+		}
+		secrets[i].Body = resolved
+	}
+
+	return secrets, nil
+}
+
+func getBody(opts *SetOptions) ([]byte, error) {
+	if opts.Body != "" {
+		if path := strings.TrimPrefix(opts.Body, "@"); path != opts.Body {
+			return ioutil.ReadFile(path)
+		}
+		return []byte(opts.Body), nil
+	}
+
+	if opts.IO.CanPrompt() {
+		var bodyInput string
+		err := prompt.SurveyAskOne(&survey.Password{
+			Message: "Paste your secret",
+		}, &bodyInput)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(opts.IO.Out)
+		return []byte(bodyInput), nil
+	}
+
+	body, err := ioutil.ReadAll(opts.IO.In)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from standard input: %w", err)
+	}
+
+	return bytes.TrimRight(body, "\r\n"), nil
+}
+
+// parseDotenv reads a dotenv-formatted file, honoring comments, quoted
+// values, and multiline values written as KEY="line1\nline2".
+func parseDotenv(r io.Reader) ([]namedSecret, error) {
+	var secrets []namedSecret
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid line in env file: %q", line)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+
+		if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"') {
+			unquoted := value[1 : len(value)-1]
+			unquoted = strings.ReplaceAll(unquoted, `\n`, "\n")
+			unquoted = strings.ReplaceAll(unquoted, `\"`, `"`)
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+
+		secrets = append(secrets, namedSecret{Name: name, Body: []byte(value)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+func encryptSecret(opts *SetOptions, peersPubKey [32]byte, body []byte) ([]byte, error) {
+	randReader := rand.Reader
+	if opts.RandomOverride != nil {
+		randReader = opts.RandomOverride
+	}
+	return box.SealAnonymous(nil, body, &peersPubKey, randReader)
+}
+
+type PubKey struct {
+	ID  string `json:"key_id"`
+	Key string `json:"key"`
+}
+
+func (pk *PubKey) Raw() [32]byte {
+	decoded, _ := base64.StdEncoding.DecodeString(pk.Key)
+	var raw [32]byte
+	copy(raw[:], decoded)
+	return raw
+}
+
+type SecretPayload struct {
+	EncryptedValue string            `json:"encrypted_value"`
+	Visibility     shared.Visibility `json:"visibility,omitempty"`
+	Repositories   []int             `json:"selected_repository_ids,omitempty"`
+	KeyID          string            `json:"key_id"`
+}
+
+type CodespacesSecretPayload struct {
+	EncryptedValue string   `json:"encrypted_value"`
+	KeyID          string   `json:"key_id"`
+	Repositories   []string `json:"selected_repository_ids,omitempty"`
+}
+
+func getPubKey(client *api.Client, host, path string) (*PubKey, error) {
+	pk := PubKey{}
+	err := client.REST(host, "GET", path, nil, &pk)
+	if err != nil {
+		return nil, err
+	}
+	if pk.Key == "" {
+		return nil, fmt.Errorf("failed to find public key at %s/%s", host, path)
+	}
+	return &pk, nil
+}
+
+// mapRepoNamesToIDs resolves a list of repository names (either "repo" or
+// "owner/repo") to their numeric database IDs via a single batched GraphQL
+// query, so that org/user secret visibility can be restricted to them.
+func mapRepoNamesToIDs(client *api.Client, host, fallbackOwner string, repositoryNames []string) ([]int64, error) {
+	queries := make([]string, 0, len(repositoryNames))
+	for i, repositoryName := range repositoryNames {
+		owner, name := splitRepositoryName(repositoryName, fallbackOwner)
+		queries = append(queries, fmt.Sprintf(
+			`repo_%04d: repository(owner: %q, name: %q) { databaseId }`, i+1, owner, name))
+	}
+
+	query := fmt.Sprintf(`query MapRepositoryNames { %s }`, strings.Join(queries, "\n"))
+
+	graphqlResult := make(map[string]*struct {
+		DatabaseID int64 `json:"databaseId"`
+	})
+
+	if err := client.GraphQL(host, query, nil, &graphqlResult); err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]int64, len(repositoryNames))
+	for i := range repositoryNames {
+		key := fmt.Sprintf("repo_%04d", i+1)
+		result, ok := graphqlResult[key]
+		if !ok || result == nil {
+			return nil, fmt.Errorf("could not resolve repository %q", repositoryNames[i])
+		}
+		repoIDs[i] = result.DatabaseID
+	}
+
+	return repoIDs, nil
+}
+
+func splitRepositoryName(repositoryName, fallbackOwner string) (owner, name string) {
+	if idx := strings.IndexByte(repositoryName, '/'); idx >= 0 {
+		return repositoryName[:idx], repositoryName[idx+1:]
+	}
+	return fallbackOwner, repositoryName
+}
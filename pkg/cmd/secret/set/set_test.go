@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/cli/cli/v2/internal/config"
 	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/secret/policy"
 	"github.com/cli/cli/v2/pkg/cmd/secret/shared"
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/cli/cli/v2/pkg/httpmock"
@@ -483,6 +486,239 @@ func Test_getBody(t *testing.T) {
 	}
 }
 
+type fakeAuditSink struct {
+	entries []shared.AuditEntry
+}
+
+func (s *fakeAuditSink) Append(entry shared.AuditEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func Test_setRun_audit(t *testing.T) {
+	reg := &httpmock.Registry{}
+
+	reg.Register(httpmock.REST("GET", "repos/owner/repo/actions/secrets/public-key"),
+		httpmock.JSONResponse(PubKey{ID: "123", Key: "CDjXqf7AJBXWhMczcy+Fs7JlACEptgceysutztHaFQI="}))
+
+	reg.Register(httpmock.REST("PUT", "repos/owner/repo/actions/secrets/cool_secret"), httpmock.StatusStringResponse(201, `{}`))
+
+	io, _, _, _ := iostreams.Test()
+
+	sink := &fakeAuditSink{}
+
+	opts := &SetOptions{
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		Config: func() (config.Config, error) { return config.NewBlankConfig(), nil },
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("owner/repo")
+		},
+		IO:             io,
+		SecretName:     "cool_secret",
+		Body:           "a secret",
+		Audit:          true,
+		AuditSink:      sink,
+		RandomOverride: bytes.NewReader([]byte{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}),
+	}
+
+	err := setRun(opts)
+	assert.NoError(t, err)
+
+	reg.Verify(t)
+
+	assert.Len(t, sink.entries, 1)
+	assert.Equal(t, "cool_secret", sink.entries[0].SecretName)
+	assert.Equal(t, "123", sink.entries[0].KeyID)
+	assert.Equal(t, shared.Repository, sink.entries[0].Scope)
+	assert.NotEmpty(t, sink.entries[0].CiphertextSHA256)
+}
+
+func Test_setRun_policyViolation(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(httpmock.REST("GET", "repos/owner/repo/actions/secrets/public-key"),
+		httpmock.JSONResponse(PubKey{ID: "123", Key: "CDjXqf7AJBXWhMczcy+Fs7JlACEptgceysutztHaFQI="}))
+
+	io, _, _, _ := iostreams.Test()
+
+	opts := &SetOptions{
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		Config: func() (config.Config, error) { return config.NewBlankConfig(), nil },
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("owner/repo")
+		},
+		IO:         io,
+		SecretName: "AWS_SECRET",
+		Body:       "a secret",
+		Policy: &policy.Policy{
+			Scopes: map[policy.Scope]policy.ScopeRules{
+				policy.ScopeRepository: {NameDeny: []string{"^AWS_"}},
+			},
+		},
+		RandomOverride: bytes.NewReader([]byte{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}),
+	}
+
+	err := setRun(opts)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "violates policy")
+
+	// No PUT was registered above, so reg.Verify would fail if setRun had
+	// gone ahead and made the request.
+}
+
+func Test_setRun_policyWarn(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(httpmock.REST("GET", "repos/owner/repo/actions/secrets/public-key"),
+		httpmock.JSONResponse(PubKey{ID: "123", Key: "CDjXqf7AJBXWhMczcy+Fs7JlACEptgceysutztHaFQI="}))
+
+	reg.Register(httpmock.REST("PUT", "repos/owner/repo/actions/secrets/AWS_SECRET"), httpmock.StatusStringResponse(201, `{}`))
+
+	io, _, _, stderr := iostreams.Test()
+
+	opts := &SetOptions{
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		Config: func() (config.Config, error) { return config.NewBlankConfig(), nil },
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("owner/repo")
+		},
+		IO:         io,
+		SecretName: "AWS_SECRET",
+		Body:       "a secret",
+		Policy: &policy.Policy{
+			Scopes: map[policy.Scope]policy.ScopeRules{
+				policy.ScopeRepository: {NameDeny: []string{"^AWS_"}},
+			},
+		},
+		PolicyWarn:     true,
+		RandomOverride: bytes.NewReader([]byte{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}),
+	}
+
+	err := setRun(opts)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "Policy warning")
+}
+
+func Test_setRun_dryRun(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(httpmock.REST("GET", "repos/owner/repo/actions/secrets/public-key"),
+		httpmock.JSONResponse(PubKey{ID: "123", Key: "CDjXqf7AJBXWhMczcy+Fs7JlACEptgceysutztHaFQI="}))
+
+	io, _, stdout, stderr := iostreams.Test()
+
+	opts := &SetOptions{
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		Config: func() (config.Config, error) { return config.NewBlankConfig(), nil },
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("owner/repo")
+		},
+		IO:             io,
+		SecretName:     "cool_secret",
+		Body:           "a secret",
+		DryRun:         true,
+		RandomOverride: bytes.NewReader([]byte{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}),
+	}
+
+	err := setRun(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "", stderr.String())
+
+	var manifests []dryRunManifest
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+	assert.Len(t, manifests, 1)
+	assert.Equal(t, "cool_secret", manifests[0].SecretName)
+	assert.Equal(t, "123", manifests[0].KeyID)
+	assert.NotZero(t, manifests[0].CiphertextBytes)
+	assert.NotEmpty(t, manifests[0].ContentHash)
+	assert.Equal(t, "", manifests[0].Operation)
+}
+
+func Test_setRun_dryRunDiff(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(httpmock.REST("GET", "repos/owner/repo/actions/secrets/public-key"),
+		httpmock.JSONResponse(PubKey{ID: "123", Key: "CDjXqf7AJBXWhMczcy+Fs7JlACEptgceysutztHaFQI="}))
+	reg.Register(httpmock.REST("GET", "repos/owner/repo/actions/secrets/cool_secret"),
+		httpmock.StatusStringResponse(404, `{}`))
+
+	io, _, stdout, _ := iostreams.Test()
+
+	opts := &SetOptions{
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		Config: func() (config.Config, error) { return config.NewBlankConfig(), nil },
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("owner/repo")
+		},
+		IO:             io,
+		SecretName:     "cool_secret",
+		Body:           "a secret",
+		DryRun:         true,
+		DryRunDiff:     true,
+		RandomOverride: bytes.NewReader([]byte{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}),
+	}
+
+	err := setRun(opts)
+	assert.NoError(t, err)
+
+	var manifests []dryRunManifest
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+	assert.Len(t, manifests, 1)
+	assert.Equal(t, "create", manifests[0].Operation)
+}
+
+func Test_getBody_fromFile(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+	io.SetStdinTTY(false)
+
+	f, err := ioutil.TempFile("", "gh-secret-test")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString("a secret from disk")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	body, err := getBody(&SetOptions{
+		Body: "@" + f.Name(),
+		IO:   io,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a secret from disk", string(body))
+}
+
+func Test_parseDotenv(t *testing.T) {
+	input := strings.NewReader(`
+# a comment
+FIRST=one
+SECOND="line1\nline2"
+THIRD='single quoted'
+`)
+
+	secrets, err := parseDotenv(input)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []namedSecret{
+		{Name: "FIRST", Body: []byte("one")},
+		{Name: "SECOND", Body: []byte("line1\nline2")},
+		{Name: "THIRD", Body: []byte("single quoted")},
+	}, secrets)
+}
+
 func Test_getBodyPrompt(t *testing.T) {
 	io, _, _, _ := iostreams.Test()
 
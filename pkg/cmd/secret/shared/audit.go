@@ -0,0 +1,166 @@
+package shared
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AuditEntry is a single record in the secret-audit log: one per
+// successful `gh secret set`.
+type AuditEntry struct {
+	Timestamp        time.Time    `json:"timestamp"`
+	Actor            string       `json:"actor"`
+	Scope            SecretEntity `json:"scope"`
+	SecretName       string       `json:"secret_name"`
+	CiphertextSHA256 string       `json:"ciphertext_sha256"`
+	KeyID            string       `json:"key_id"`
+	PrevHash         string       `json:"prev_hash"`
+	Hash             string       `json:"hash"`
+}
+
+// contentHash returns the hash that chains this entry to the one before
+// it: sha256(PrevHash || every other field). It does not cover Hash
+// itself, since that is the value being computed.
+func (e AuditEntry) contentHash() (string, error) {
+	unhashed := e
+	unhashed.Hash = ""
+	data, err := json.Marshal(unhashed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditSink records a successful secret-set operation. SetOptions.AuditSink
+// is an AuditSink so that tests can substitute an in-memory fake.
+type AuditSink interface {
+	Append(entry AuditEntry) error
+}
+
+// DefaultAuditLogPath returns ~/.config/gh/secret-audit.log.
+func DefaultAuditLogPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "gh", "secret-audit.log"), nil
+}
+
+// FileAuditSink appends newline-delimited JSON audit entries to a local
+// file, chaining each new entry to the hash of the last line already in
+// the file.
+type FileAuditSink struct {
+	Path string
+}
+
+func (s *FileAuditSink) Append(entry AuditEntry) error {
+	prevHash, err := lastHash(s.Path)
+	if err != nil {
+		return err
+	}
+	entry.PrevHash = prevHash
+
+	hash, err := entry.contentHash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// lastHash returns the Hash field of the final entry in the log at path,
+// or "" if the log does not exist yet or is empty.
+func lastHash(path string) (string, error) {
+	entries, err := ReadAuditLog(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+	return entries[len(entries)-1].Hash, nil
+}
+
+// ReadAuditLog parses every entry out of the audit log at path, in order.
+func ReadAuditLog(path string) ([]AuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("could not parse audit log entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// VerifyAuditLog re-derives the hash chain for the log at path and reports
+// the index of the first entry whose Hash or PrevHash no longer matches,
+// or -1 if the whole chain verifies.
+func VerifyAuditLog(path string) (brokenAt int, err error) {
+	entries, err := ReadAuditLog(path)
+	if err != nil {
+		return -1, err
+	}
+
+	prevHash := ""
+	for i, entry := range entries {
+		if entry.PrevHash != prevHash {
+			return i, nil
+		}
+		want := entry
+		want.Hash = ""
+		hash, err := want.contentHash()
+		if err != nil {
+			return -1, err
+		}
+		if hash != entry.Hash {
+			return i, nil
+		}
+		prevHash = entry.Hash
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FileAuditSink_chain(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret-audit.log")
+	sink := &FileAuditSink{Path: path}
+
+	err := sink.Append(AuditEntry{Timestamp: time.Now(), Actor: "monalisa", Scope: Repository, SecretName: "cool_secret", CiphertextSHA256: "aaa", KeyID: "123"})
+	assert.NoError(t, err)
+	err = sink.Append(AuditEntry{Timestamp: time.Now(), Actor: "monalisa", Scope: Repository, SecretName: "good_secret", CiphertextSHA256: "bbb", KeyID: "123"})
+	assert.NoError(t, err)
+
+	entries, err := ReadAuditLog(path)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "", entries[0].PrevHash)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+	assert.NotEmpty(t, entries[1].Hash)
+
+	brokenAt, err := VerifyAuditLog(path)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, brokenAt)
+}
+
+func Test_VerifyAuditLog_detectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret-audit.log")
+	sink := &FileAuditSink{Path: path}
+
+	assert.NoError(t, sink.Append(AuditEntry{Timestamp: time.Now(), Actor: "monalisa", Scope: Repository, SecretName: "cool_secret", CiphertextSHA256: "aaa", KeyID: "123"}))
+	assert.NoError(t, sink.Append(AuditEntry{Timestamp: time.Now(), Actor: "monalisa", Scope: Repository, SecretName: "good_secret", CiphertextSHA256: "bbb", KeyID: "123"}))
+
+	entries, err := ReadAuditLog(path)
+	assert.NoError(t, err)
+	entries[0].SecretName = "tampered_secret"
+
+	tamperedPath := filepath.Join(dir, "tampered.log")
+	var lines []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		assert.NoError(t, err)
+		lines = append(lines, append(line, '\n')...)
+	}
+	assert.NoError(t, os.WriteFile(tamperedPath, lines, 0o600))
+
+	brokenAt, err := VerifyAuditLog(tamperedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, brokenAt)
+}
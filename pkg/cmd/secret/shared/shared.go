@@ -0,0 +1,41 @@
+package shared
+
+import "fmt"
+
+type Visibility string
+
+const (
+	All      Visibility = "all"
+	Private  Visibility = "private"
+	Selected Visibility = "selected"
+)
+
+// SecretEntity is the level at which a secret is stored.
+type SecretEntity string
+
+const (
+	Repository   SecretEntity = "repository"
+	Organization SecretEntity = "organization"
+	Environment  SecretEntity = "environment"
+	User         SecretEntity = "user"
+)
+
+func GetSecretEntity(userSecrets bool, orgName, envName string) (SecretEntity, error) {
+	switch {
+	case userSecrets:
+		return User, nil
+	case orgName != "":
+		return Organization, nil
+	case envName != "":
+		return Environment, nil
+	default:
+		return Repository, nil
+	}
+}
+
+func ValidateSecretName(name string) error {
+	if name == "" {
+		return fmt.Errorf("secret name cannot be blank")
+	}
+	return nil
+}